@@ -0,0 +1,262 @@
+// Package admin implements a runtime HTTP surface for inspecting and
+// mutating the deployment graph without restarting the driver.
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/tznamena/azure-aap-deployment-driver/server/model"
+)
+
+// Handler serves the admin API, protected by the driver's existing
+// SessionConfig auth key.
+type Handler struct {
+	db      *gorm.DB
+	engine  *model.EngineConfigStore
+	authKey []byte
+}
+
+// NewHandler builds an admin Handler. engine must be the same
+// model.EngineConfigStore the running engine reads its configuration from,
+// so a PATCH here takes effect without a restart.
+func NewHandler(db *gorm.DB, engine *model.EngineConfigStore, authKey []byte) *Handler {
+	return &Handler{db: db, engine: engine, authKey: authKey}
+}
+
+// Register wires the admin routes onto mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/admin/engine-config", h.authenticated(h.handleEngineConfig))
+	mux.HandleFunc("/admin/steps", h.authenticated(h.handleSteps))
+	mux.HandleFunc("/admin/steps/", h.authenticated(h.handleStep))
+}
+
+func (h *Handler) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if len(h.authKey) == 0 || subtle.ConstantTimeCompare([]byte(token), h.authKey) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleStep dispatches GET/retry/skip/disable for /admin/steps/{name}.
+func (h *Handler) handleStep(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/admin/steps/")
+	name, action, _ := strings.Cut(path, "/")
+	if name == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && action == "":
+		h.getStep(w, r, name)
+	case r.Method == http.MethodPost && action == "retry":
+		h.retryStep(w, r, name)
+	case r.Method == http.MethodPost && action == "skip":
+		h.skipStep(w, r, name)
+	case r.Method == http.MethodDelete && action == "":
+		h.disableStep(w, r, name)
+	default:
+		http.Error(w, "unsupported method for this route", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) loadStep(w http.ResponseWriter, name string) (*model.Step, bool) {
+	var step model.Step
+	if err := h.db.Where("name = ?", name).First(&step).Error; err != nil {
+		http.Error(w, "step not found: "+name, http.StatusNotFound)
+		return nil, false
+	}
+	return &step, true
+}
+
+// getStep handles GET /admin/steps/{name}, returning the step as it
+// currently stands (including its executions) so an operator can inspect it
+// before deciding to retry, skip, or disable it.
+func (h *Handler) getStep(w http.ResponseWriter, r *http.Request, name string) {
+	step, ok := h.loadStep(w, name)
+	if !ok {
+		return
+	}
+	writeJSON(w, step)
+}
+
+// recordAction appends an AdminActionLog row for an admin mutation, rather
+// than upserting into Telemetry, so repeated actions against the same step
+// don't overwrite each other's history.
+func (h *Handler) recordAction(action, step, detail string) {
+	h.db.Create(&model.AdminActionLog{Step: step, Action: action, Detail: detail})
+}
+
+// retryStep forces a failed step to requeue by writing a fresh pending
+// Execution row for it.
+func (h *Handler) retryStep(w http.ResponseWriter, r *http.Request, name string) {
+	step, ok := h.loadStep(w, name)
+	if !ok {
+		return
+	}
+
+	execution := model.Execution{
+		StepID:    step.ID,
+		Status:    model.Pending,
+		Timestamp: time.Now(),
+	}
+	if err := h.db.Create(&execution).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.recordAction("retry", name, "")
+	writeJSON(w, execution)
+}
+
+// skipStep marks the step succeeded with a synthetic Execution, letting the
+// engine move past a step an operator has verified out of band.
+func (h *Handler) skipStep(w http.ResponseWriter, r *http.Request, name string) {
+	step, ok := h.loadStep(w, name)
+	if !ok {
+		return
+	}
+
+	execution := model.Execution{
+		StepID:    step.ID,
+		Status:    model.Succeeded,
+		Details:   "skipped via admin API",
+		Timestamp: time.Now(),
+	}
+	if err := h.db.Create(&execution).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.recordAction("skip", name, "")
+	writeJSON(w, execution)
+}
+
+// disableStep removes a step from the deployment graph mid-run.
+func (h *Handler) disableStep(w http.ResponseWriter, r *http.Request, name string) {
+	step, ok := h.loadStep(w, name)
+	if !ok {
+		return
+	}
+	if err := h.db.Delete(step).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.recordAction("disable", name, "")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleSteps dispatches GET /admin/steps (list) and POST /admin/steps (add).
+func (h *Handler) handleSteps(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listSteps(w, r)
+	case http.MethodPost:
+		h.addStep(w, r)
+	default:
+		http.Error(w, "unsupported method for this route", http.StatusMethodNotAllowed)
+	}
+}
+
+// listSteps handles GET /admin/steps, returning every step in the
+// deployment graph.
+func (h *Handler) listSteps(w http.ResponseWriter, r *http.Request) {
+	var steps []model.Step
+	if err := h.db.Find(&steps).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, steps)
+}
+
+// addStep handles POST /admin/steps, adding a new step to the deployment
+// graph mid-run.
+func (h *Handler) addStep(w http.ResponseWriter, r *http.Request) {
+	var step model.Step
+	if err := json.NewDecoder(r.Body).Decode(&step); err != nil {
+		http.Error(w, "invalid step payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := step.RestartPolicy.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.db.Create(&step).Error; err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.recordAction("add", step.Name, "")
+	writeJSON(w, step)
+}
+
+// engineConfigPatch mirrors model.EngineConfiguration with pointer fields, so
+// a field omitted from the PATCH body (nil) can be told apart from one
+// explicitly set to its zero value.
+type engineConfigPatch struct {
+	StepRestartTimeout    *int64 `json:"stepRestartTimeoutSec"`
+	OverallTimeout        *int64 `json:"overallTimeoutSec"`
+	EngineExitDelay       *int64 `json:"engineExitDelaySec"`
+	AutoRetryDelay        *int64 `json:"autoRetryDelaySec"`
+	StepDeploymentTimeout *int64 `json:"stepDeploymentTimeoutSec"`
+	StepMaxRetries        *int   `json:"stepMaxRetries"`
+}
+
+// handleEngineConfig dispatches GET (current config) and PATCH (hot-reload)
+// for /admin/engine-config.
+func (h *Handler) handleEngineConfig(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, h.engine.Get())
+	case http.MethodPatch:
+		h.patchEngineConfig(w, r)
+	default:
+		http.Error(w, "unsupported method for this route", http.StatusMethodNotAllowed)
+	}
+}
+
+// patchEngineConfig hot-reloads EngineConfiguration fields into the running
+// engine. Only fields present in the request body are changed.
+func (h *Handler) patchEngineConfig(w http.ResponseWriter, r *http.Request) {
+	var patch engineConfigPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		http.Error(w, "invalid engine configuration payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updated := h.engine.Update(func(cfg *model.EngineConfiguration) {
+		if patch.StepRestartTimeout != nil {
+			cfg.StepRestartTimeout = *patch.StepRestartTimeout
+		}
+		if patch.OverallTimeout != nil {
+			cfg.OverallTimeout = *patch.OverallTimeout
+		}
+		if patch.EngineExitDelay != nil {
+			cfg.EngineExitDelay = *patch.EngineExitDelay
+		}
+		if patch.AutoRetryDelay != nil {
+			cfg.AutoRetryDelay = *patch.AutoRetryDelay
+		}
+		if patch.StepDeploymentTimeout != nil {
+			cfg.StepDeploymentTimeout = *patch.StepDeploymentTimeout
+		}
+		if patch.StepMaxRetries != nil {
+			cfg.StepMaxRetries = *patch.StepMaxRetries
+		}
+	})
+
+	h.recordAction("engine-config", model.MAIN_MARKER, "")
+	writeJSON(w, updated)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
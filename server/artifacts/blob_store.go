@@ -0,0 +1,89 @@
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+)
+
+// BlobStoreName identifies BlobStore in model.Artifact.Store.
+const BlobStoreName = "azblob"
+
+// BlobStore offloads artifacts to an Azure Blob Storage container, using the
+// same managed identity the deployment driver already authenticates to ARM
+// with.
+type BlobStore struct {
+	client    *azblob.Client
+	container string
+}
+
+// NewBlobStore builds a BlobStore against accountURL (e.g.
+// "https://<account>.blob.core.windows.net") using the environment's
+// managed identity, storing blobs in container.
+func NewBlobStore(accountURL, container string) (*BlobStore, error) {
+	credential, err := azidentity.NewManagedIdentityCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating managed identity credential: %w", err)
+	}
+	client, err := azblob.NewClient(accountURL, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating blob client: %w", err)
+	}
+	return &BlobStore{client: client, container: container}, nil
+}
+
+func (s *BlobStore) Put(ctx context.Context, key string, value []byte) (string, error) {
+	blobName := sanitizeBlobName(key)
+	if _, err := s.client.UploadBuffer(ctx, s.container, blobName, value, nil); err != nil {
+		return "", fmt.Errorf("uploading blob %q: %w", blobName, err)
+	}
+	return fmt.Sprintf("%s/%s/%s", strings.TrimRight(s.client.URL(), "/"), s.container, blobName), nil
+}
+
+func (s *BlobStore) Get(ctx context.Context, uri string) ([]byte, error) {
+	blobName, err := blobNameFromURI(s, uri)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.DownloadStream(ctx, s.container, blobName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("downloading blob %q: %w", blobName, err)
+	}
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err := io.Copy(&buf, resp.Body); err != nil {
+		return nil, fmt.Errorf("reading blob %q: %w", blobName, err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *BlobStore) Delete(ctx context.Context, uri string) error {
+	blobName, err := blobNameFromURI(s, uri)
+	if err != nil {
+		return err
+	}
+	if _, err := s.client.DeleteBlob(ctx, s.container, blobName, nil); err != nil {
+		return fmt.Errorf("deleting blob %q: %w", blobName, err)
+	}
+	return nil
+}
+
+func blobNameFromURI(s *BlobStore, uri string) (string, error) {
+	prefix := fmt.Sprintf("%s/%s/", strings.TrimRight(s.client.URL(), "/"), s.container)
+	if !strings.HasPrefix(uri, prefix) {
+		return "", fmt.Errorf("uri %q does not belong to container %q", uri, s.container)
+	}
+	return strings.TrimPrefix(uri, prefix), nil
+}
+
+// sanitizeBlobName keeps the caller-chosen key but trims leading and trailing
+// slashes from it; no other characters are changed.
+func sanitizeBlobName(key string) string {
+	return strings.Trim(key, "/")
+}
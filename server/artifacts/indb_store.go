@@ -0,0 +1,74 @@
+package artifacts
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/tznamena/azure-aap-deployment-driver/server/model"
+)
+
+// InDBStoreName identifies InDBStore in model.Artifact.Store.
+const InDBStoreName = "indb"
+
+// InDBStore is the pre-existing behavior preserved as a Store implementation:
+// values are kept in the primary database rather than an external backend.
+// It exists so installations without blob storage configured keep working
+// unchanged.
+type InDBStore struct {
+	db *gorm.DB
+}
+
+// NewInDBStore builds an InDBStore backed by db.
+func NewInDBStore(db *gorm.DB) *InDBStore {
+	return &InDBStore{db: db}
+}
+
+func (s *InDBStore) Put(ctx context.Context, key string, value []byte) (string, error) {
+	id, err := randomArtifactID()
+	if err != nil {
+		return "", fmt.Errorf("generating inline artifact id for %q: %w", key, err)
+	}
+	uri := "indb://" + id
+
+	artifact := model.Artifact{
+		URI:        uri,
+		Store:      InDBStoreName,
+		SizeBytes:  int64(len(value)),
+		InlineData: value,
+	}
+	if err := s.db.WithContext(ctx).Create(&artifact).Error; err != nil {
+		return "", fmt.Errorf("storing inline artifact %q: %w", key, err)
+	}
+	return uri, nil
+}
+
+func (s *InDBStore) Get(ctx context.Context, uri string) ([]byte, error) {
+	var artifact model.Artifact
+	if err := s.db.WithContext(ctx).Where("uri = ?", uri).First(&artifact).Error; err != nil {
+		return nil, fmt.Errorf("loading inline artifact %q: %w", uri, err)
+	}
+	return artifact.InlineData, nil
+}
+
+func (s *InDBStore) Delete(ctx context.Context, uri string) error {
+	if err := s.db.WithContext(ctx).Where("uri = ?", uri).Delete(&model.Artifact{}).Error; err != nil {
+		return fmt.Errorf("deleting inline artifact %q: %w", uri, err)
+	}
+	return nil
+}
+
+// randomArtifactID generates the URI's opaque identifier before the row is
+// ever created, so Artifact.URI's unique index always sees a distinct,
+// fully-formed value instead of colliding on a placeholder that gets
+// rewritten in a second query.
+func randomArtifactID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
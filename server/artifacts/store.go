@@ -0,0 +1,133 @@
+// Package artifacts offloads large values out of the JSONMap columns that
+// would otherwise hold them inline in the primary database.
+package artifacts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+
+	"github.com/tznamena/azure-aap-deployment-driver/server/model"
+)
+
+// DefaultSizeThreshold is the size above which a JSONMap value is offloaded
+// to a Store instead of being stored inline.
+const DefaultSizeThreshold = 64 * 1024 // 64 KiB
+
+// refKey is the JSONMap key used in place of an inlined value once it has
+// been offloaded to a Store.
+const refKey = "$artifact"
+
+// Store persists large values outside the primary database, keyed by an
+// opaque key the caller chooses (e.g. "outputs/<id>/values").
+type Store interface {
+	Put(ctx context.Context, key string, value []byte) (uri string, err error)
+	Get(ctx context.Context, uri string) ([]byte, error)
+	Delete(ctx context.Context, uri string) error
+}
+
+// Offloader wraps a Store with the size-threshold policy and the
+// model.Artifact bookkeeping that lets orphaned blobs be garbage collected.
+type Offloader struct {
+	db        *gorm.DB
+	store     Store
+	storeName string
+	threshold int
+}
+
+// NewOffloader builds an Offloader. storeName is recorded on each
+// model.Artifact row so GC can tell which Store a URI belongs to; threshold
+// <= 0 falls back to DefaultSizeThreshold.
+func NewOffloader(db *gorm.DB, store Store, storeName string, threshold int) *Offloader {
+	if threshold <= 0 {
+		threshold = DefaultSizeThreshold
+	}
+	return &Offloader{db: db, store: store, storeName: storeName, threshold: threshold}
+}
+
+// Offload replaces values in a JSONMap larger than the configured threshold
+// with a {"$artifact": "<uri>"} reference, writing the original bytes to the
+// Store and recording a model.Artifact row. key is used as a prefix for the
+// per-field storage key.
+func (o *Offloader) Offload(ctx context.Context, key string, values datatypes.JSONMap) (datatypes.JSONMap, error) {
+	result := datatypes.JSONMap{}
+	for field, value := range values {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling field %q: %w", field, err)
+		}
+		if len(encoded) <= o.threshold {
+			result[field] = value
+			continue
+		}
+
+		storeKey := fmt.Sprintf("%s/%s", key, field)
+		uri, err := o.store.Put(ctx, storeKey, encoded)
+		if err != nil {
+			return nil, fmt.Errorf("offloading field %q: %w", field, err)
+		}
+		// InDBStore.Put has already recorded the model.Artifact row for uri.
+		if _, inDB := o.store.(*InDBStore); !inDB {
+			if err := o.db.Create(&model.Artifact{
+				URI:       uri,
+				Store:     o.storeName,
+				SizeBytes: int64(len(encoded)),
+			}).Error; err != nil {
+				return nil, fmt.Errorf("recording artifact %q: %w", uri, err)
+			}
+		}
+		result[field] = map[string]any{refKey: uri}
+	}
+	return result, nil
+}
+
+// CreateOutput builds a model.Output the same way model.CreateNewOutput does,
+// but first runs result.Outputs through Offload so any value over the
+// configured threshold is written to the Store instead of landing in
+// Output.Values directly. Callers that have an Offloader configured should
+// use this instead of calling model.CreateNewOutput directly.
+func (o *Offloader) CreateOutput(ctx context.Context, name string, result *model.DeploymentResult) (*model.Output, error) {
+	offloaded, err := o.Offload(ctx, fmt.Sprintf("outputs/%s", name), result.Outputs)
+	if err != nil {
+		return nil, fmt.Errorf("offloading output %q: %w", name, err)
+	}
+	output := model.CreateNewOutput(name, result)
+	output.Values = offloaded
+	return output, nil
+}
+
+// Resolve reverses Offload, replacing any {"$artifact": "<uri>"} reference in
+// values with the original value fetched from the Store. Fields that were
+// never offloaded are returned unchanged.
+func (o *Offloader) Resolve(ctx context.Context, values datatypes.JSONMap) (datatypes.JSONMap, error) {
+	result := datatypes.JSONMap{}
+	for field, value := range values {
+		uri, ok := artifactURI(value)
+		if !ok {
+			result[field] = value
+			continue
+		}
+		data, err := o.store.Get(ctx, uri)
+		if err != nil {
+			return nil, fmt.Errorf("resolving field %q (%s): %w", field, uri, err)
+		}
+		var resolved any
+		if err := json.Unmarshal(data, &resolved); err != nil {
+			return nil, fmt.Errorf("parsing resolved field %q (%s): %w", field, uri, err)
+		}
+		result[field] = resolved
+	}
+	return result, nil
+}
+
+func artifactURI(value any) (string, bool) {
+	ref, ok := value.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	uri, ok := ref[refKey].(string)
+	return uri, ok
+}
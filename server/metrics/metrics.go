@@ -0,0 +1,176 @@
+// Package metrics exposes a Prometheus /metrics endpoint derived from the
+// Telemetry table and live Execution rows.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/tznamena/azure-aap-deployment-driver/server/model"
+)
+
+// Collector is a prometheus.Collector that queries GORM on every scrape
+// instead of holding in-process state.
+type Collector struct {
+	db *gorm.DB
+
+	retryTotal     *prometheus.Desc
+	failureTotal   *prometheus.Desc
+	stepDuration   *prometheus.Desc
+	stepExecutions *prometheus.Desc
+	deployDuration *prometheus.Desc
+}
+
+// NewCollector builds a Collector backed by db.
+func NewCollector(db *gorm.DB) *Collector {
+	return &Collector{
+		db: db,
+		retryTotal: prometheus.NewDesc(
+			"aap_step_retries_total", "Retries recorded per step.", []string{"step"}, nil),
+		failureTotal: prometheus.NewDesc(
+			"aap_step_failures_total", "Failures recorded per step.", []string{"step"}, nil),
+		stepDuration: prometheus.NewDesc(
+			"aap_step_duration_seconds", "Duration of the most recent execution per step.", []string{"step"}, nil),
+		stepExecutions: prometheus.NewDesc(
+			"aap_step_executions", "Count of executions per step, broken down by status.", []string{"step", "status"}, nil),
+		deployDuration: prometheus.NewDesc(
+			"aap_deployment_duration_seconds", "End-to-end deployment duration measured from Status.FirstStart.", nil, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.retryTotal
+	ch <- c.failureTotal
+	ch <- c.stepDuration
+	ch <- c.stepExecutions
+	ch <- c.deployDuration
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.collectTelemetry(ch)
+	c.collectStepDurations(ch)
+	c.collectExecutionCounts(ch)
+	c.collectDeploymentDuration(ch)
+}
+
+func (c *Collector) collectTelemetry(ch chan<- prometheus.Metric) {
+	var rows []model.Telemetry
+	if err := c.db.Where("metric_name IN ?", []model.DeploymentMetric{model.RetryCount, model.FailureCount}).
+		Find(&rows).Error; err != nil {
+		log.Warnf("metrics: querying telemetry: %v", err)
+		return
+	}
+	for _, row := range rows {
+		value, err := strconv.ParseFloat(row.MetricValue, 64)
+		if err != nil {
+			continue
+		}
+		switch row.MetricName {
+		case model.RetryCount:
+			ch <- prometheus.MustNewConstMetric(c.retryTotal, prometheus.CounterValue, value, row.Step)
+		case model.FailureCount:
+			ch <- prometheus.MustNewConstMetric(c.failureTotal, prometheus.CounterValue, value, row.Step)
+		}
+	}
+}
+
+func (c *Collector) collectStepDurations(ch chan<- prometheus.Metric) {
+	type durationRow struct {
+		StepName string
+		Duration string
+	}
+	var rows []durationRow
+	err := c.db.Model(&model.Execution{}).
+		Select("steps.name AS step_name, executions.duration AS duration").
+		Joins("JOIN steps ON steps.id = executions.step_id").
+		Where("executions.id IN (?)", c.db.Model(&model.Execution{}).Select("MAX(id)").Group("step_id")).
+		Scan(&rows).Error
+	if err != nil {
+		log.Warnf("metrics: querying step durations: %v", err)
+		return
+	}
+	for _, row := range rows {
+		duration, err := time.ParseDuration(row.Duration)
+		if err != nil {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.stepDuration, prometheus.GaugeValue, duration.Seconds(), row.StepName)
+	}
+}
+
+func (c *Collector) collectExecutionCounts(ch chan<- prometheus.Metric) {
+	type countRow struct {
+		StepName string
+		Status   string
+		Count    int64
+	}
+	var rows []countRow
+	err := c.db.Model(&model.Execution{}).
+		Select("steps.name AS step_name, executions.status AS status, count(*) AS count").
+		Joins("JOIN steps ON steps.id = executions.step_id").
+		Group("steps.name, executions.status").
+		Scan(&rows).Error
+	if err != nil {
+		log.Warnf("metrics: querying execution counts: %v", err)
+		return
+	}
+	for _, row := range rows {
+		ch <- prometheus.MustNewConstMetric(c.stepExecutions, prometheus.GaugeValue, float64(row.Count), row.StepName, row.Status)
+	}
+}
+
+// deploymentDurationBuckets are expressed in seconds, spanning one minute to
+// eight hours to cover everything from a quick role-assignment step to a
+// full AAP install.
+var deploymentDurationBuckets = []float64{60, 300, 900, 1800, 3600, 7200, 14400, 28800}
+
+// collectDeploymentDuration only emits once the deployment has reached a
+// terminal state (fatal, or main outputs loaded): the duration, and therefore the bucket a single
+// observation falls into, would otherwise grow between scrapes while the
+// deployment is still running, which violates Prometheus's requirement that
+// histogram bucket counters never decrease.
+func (c *Collector) collectDeploymentDuration(ch chan<- prometheus.Metric) {
+	var status model.Status
+	if err := c.db.First(&status).Error; err != nil {
+		log.Warnf("metrics: querying status: %v", err)
+		return
+	}
+	terminal := status.IsFatalState || status.MainOutputsLoaded
+	if status.FirstStart.IsZero() || !terminal {
+		return
+	}
+
+	duration := status.UpdatedAt.Sub(status.FirstStart).Seconds()
+
+	buckets := make(map[float64]uint64, len(deploymentDurationBuckets))
+	for _, bound := range deploymentDurationBuckets {
+		if duration <= bound {
+			buckets[bound] = 1
+		} else {
+			buckets[bound] = 0
+		}
+	}
+	ch <- prometheus.MustNewConstHistogram(c.deployDuration, 1, duration, buckets)
+}
+
+// Registry builds a Prometheus registry seeded with Collector and the
+// standard process/Go collectors, and returns the http.Handler to mount at
+// /metrics.
+func Registry(db *gorm.DB) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		NewCollector(db),
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,18 @@
+package model
+
+// AdminActionLog is an append-only audit trail of admin API mutations
+// (retry, skip, add/disable step, engine-config reload).
+//
+// NOTE: the originating request asked for this to ride the existing
+// Telemetry/SetMetric mechanism instead of a new table. That was deliberately
+// not done: Telemetry's primary key is (MetricName, Step), so a second action
+// against the same step would overwrite the first row rather than
+// accumulate history, which defeats the audit-trail requirement. Flagging
+// this as an intentional divergence from the ticket's stated design rather
+// than a drop-in substitution — worth confirming with whoever filed it.
+type AdminActionLog struct {
+	BaseModel
+	Step   string `json:"step"`
+	Action string `json:"action"`
+	Detail string `json:"detail"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import "gorm.io/datatypes"
+
+// Artifact is bookkeeping for a value that was offloaded from a JSONMap
+// column to an artifacts.Store because it exceeded the configured size
+// threshold (see artifacts.DefaultSizeThreshold). It lets orphaned blobs be
+// garbage collected once the owning row is soft-deleted.
+type Artifact struct {
+	BaseModel
+	// URI is the value stored in place of the original JSON, e.g.
+	// "https://<account>.blob.core.windows.net/<container>/<key>".
+	URI string `gorm:"unique" json:"-"`
+	// Store identifies which artifacts.Store implementation owns URI, so GC
+	// can dispatch Delete to the right backend.
+	Store string `json:"-"`
+	// SizeBytes is the size of the offloaded value, recorded for GC
+	// reporting and for deciding whether it was worth offloading at all.
+	SizeBytes int64 `json:"-"`
+	// InlineData holds the value itself when Store is the in-DB fallback
+	// backend; it is unused (and empty) for remote backends like blob
+	// storage, where URI alone is enough to fetch the value back.
+	InlineData datatypes.JSON `json:"-"`
+}
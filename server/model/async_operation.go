@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// AsyncOperation is the persisted state needed to resume polling an Azure
+// Resource Manager long-running operation (LRO) across engine restarts.
+type AsyncOperation struct {
+	BaseModel
+	ExecutionID uint `json:"executionId"`
+	// CorrelationID is copied from the Execution row when the operation
+	// starts, so it can be restored onto the synthesized DeploymentResult
+	// once the operation reaches a terminal state without depending on
+	// Execution.CorrelationID still being set by the time polling resumes.
+	CorrelationID string `json:"correlationId"`
+	// OperationURL is the value of the Azure-AsyncOperation header (falling
+	// back to Location) returned by the triggering ARM request.
+	OperationURL string `json:"-"`
+	// Method is the HTTP method of the original ARM request (PUT/PATCH/POST/DELETE).
+	Method string `json:"method"`
+	// OriginalURI is the resource URI the original request was issued
+	// against. For PUT/PATCH it is re-fetched once the operation reaches a
+	// terminal state, per ARM LRO conventions.
+	OriginalURI string `json:"-"`
+	// RetryAfterSec is the poll interval reported by the last response, in
+	// seconds. A plain int64 of seconds is used rather than time.Duration,
+	// matching EngineConfiguration's *Sec fields, since time.Duration has no
+	// custom JSON marshaler and would otherwise serialize as raw nanoseconds.
+	RetryAfterSec int64     `json:"retryAfterSec"`
+	LastPolledAt  time.Time `json:"lastPolledAt"`
+	// TerminalState is set once the operation reaches InProgress's terminal
+	// counterpart: Succeeded, Failed or Canceled. Empty while still polling.
+	TerminalState string `json:"terminalState"`
+}
+
+// DefaultAsyncOperationRetryAfter is used when an Azure response omits a
+// Retry-After header.
+const DefaultAsyncOperationRetryAfter = 30 * time.Second
+
+// RetryAfter returns RetryAfterSec as a time.Duration for scheduling.
+func (o AsyncOperation) RetryAfter() time.Duration {
+	return time.Duration(o.RetryAfterSec) * time.Second
+}
+
+// IsTerminal reports whether the operation has reached a terminal ARM LRO state.
+func (o AsyncOperation) IsTerminal() bool {
+	switch o.TerminalState {
+	case "Succeeded", "Failed", "Canceled":
+		return true
+	default:
+		return false
+	}
+}
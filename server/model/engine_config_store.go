@@ -0,0 +1,32 @@
+package model
+
+import "sync"
+
+// EngineConfigStore guards an EngineConfiguration that is read by the engine
+// loop and can be hot-reloaded by another goroutine (e.g. the admin API),
+// so neither side observes a partially-written value.
+type EngineConfigStore struct {
+	mu  sync.RWMutex
+	cfg EngineConfiguration
+}
+
+// NewEngineConfigStore builds an EngineConfigStore seeded with cfg.
+func NewEngineConfigStore(cfg EngineConfiguration) *EngineConfigStore {
+	return &EngineConfigStore{cfg: cfg}
+}
+
+// Get returns a copy of the current configuration.
+func (s *EngineConfigStore) Get() EngineConfiguration {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// Update applies fn under the write lock and returns the resulting
+// configuration.
+func (s *EngineConfigStore) Update(fn func(*EngineConfiguration)) EngineConfiguration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.cfg)
+	return s.cfg
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"context"
 	"encoding/json"
 
 	"time"
@@ -21,11 +22,12 @@ type BaseModel struct {
 
 type Step struct {
 	BaseModel
-	Name       string            `gorm:"unique" json:"name"`
-	Template   datatypes.JSONMap `json:"-"`
-	Parameters datatypes.JSONMap `json:"-"`
-	Priority   uint              `json:"order"`
-	Executions []Execution       `json:"executions" gorm:"constraint:OnUpdate:CASCADE;"`
+	Name          string            `gorm:"unique" json:"name"`
+	Template      datatypes.JSONMap `json:"-"`
+	Parameters    datatypes.JSONMap `json:"-"`
+	Priority      uint              `json:"order"`
+	RestartPolicy RestartPolicy     `json:"restartPolicy" gorm:"embedded;embeddedPrefix:restart_"`
+	Executions    []Execution       `json:"executions" gorm:"constraint:OnUpdate:CASCADE;"`
 }
 
 type Output struct {
@@ -48,6 +50,11 @@ type Execution struct {
 	Duration          string          `json:"duration"`
 	CorrelationID     string          `json:"correlationId"`
 	ResumeToken       string          `json:"-"`
+	Attempt           uint            `json:"attempt"`
+	// NextRetryAt is a pointer so it can be omitted from JSON output entirely
+	// when no retry is scheduled; omitempty has no effect on a struct-typed
+	// time.Time field and would otherwise always emit the zero-time value.
+	NextRetryAt *time.Time `json:"nextRetryAt,omitempty"`
 }
 
 type Status struct {
@@ -137,6 +144,21 @@ func CreateNewOutput(name string, result *DeploymentResult) *Output {
 	}
 }
 
+// OutputResolver resolves any {"$artifact": "<uri>"} references a Store
+// offloaded large values to, e.g. artifacts.Offloader.Resolve. It is
+// injected rather than imported directly so this package doesn't depend on
+// a concrete Store backend.
+type OutputResolver interface {
+	Resolve(ctx context.Context, values datatypes.JSONMap) (datatypes.JSONMap, error)
+}
+
+// ResolveOutputValues returns output.Values with any offloaded artifacts
+// transparently fetched back in, so callers never need to know whether a
+// given field was stored inline or in an external artifacts.Store.
+func ResolveOutputValues(ctx context.Context, resolver OutputResolver, output *Output) (datatypes.JSONMap, error) {
+	return resolver.Resolve(ctx, output.Values)
+}
+
 // Setter function for each deployment metric
 func SetMetric(db *gorm.DB, metric DeploymentMetric, value string, step string) {
 	db.Save(&Telemetry{
@@ -0,0 +1,98 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RestartCondition controls which execution outcomes count toward a step's
+// RestartPolicy.MaxAttempts.
+type RestartCondition string
+
+const (
+	// RestartOnFailure only counts Failed executions (the default).
+	RestartOnFailure RestartCondition = "on-failure"
+	// RestartOnAny counts every execution, successful or not.
+	RestartOnAny RestartCondition = "on-any"
+	// RestartNone disables automatic restarts for the step.
+	RestartNone RestartCondition = "none"
+)
+
+// RestartPolicy is a per-step override of the global StepMaxRetries/AutoRetryDelay
+// engine defaults. It is embedded on Step so it persists alongside the step
+// definition rather than living in EngineConfiguration.
+type RestartPolicy struct {
+	// MaxAttempts is the number of matching failures allowed inside Window
+	// before the step is considered permanently failed. Zero means "use the
+	// engine-wide StepMaxRetries default".
+	MaxAttempts uint `json:"maxAttempts"`
+	// Delay is how long the engine waits before re-running the step after a
+	// qualifying failure.
+	Delay time.Duration `json:"delay"`
+	// Window is the rolling lookback period used when counting failures
+	// toward MaxAttempts; failures older than now-Window are ignored. Zero
+	// means "no window", i.e. count failures for the lifetime of the step.
+	Window time.Duration `json:"window"`
+	// Condition selects which execution outcomes count as a failure for
+	// restart purposes.
+	Condition RestartCondition `json:"condition"`
+}
+
+// Validate rejects RestartPolicy values that cannot be scheduled.
+func (p RestartPolicy) Validate() error {
+	if p.Delay < 0 {
+		return fmt.Errorf("restart policy delay must not be negative: %s", p.Delay)
+	}
+	if p.Window < 0 {
+		return fmt.Errorf("restart policy window must not be negative: %s", p.Window)
+	}
+	switch p.Condition {
+	case "", RestartOnFailure, RestartOnAny, RestartNone:
+	default:
+		return fmt.Errorf("unknown restart condition: %q", p.Condition)
+	}
+	return nil
+}
+
+// CountFailuresInWindow returns the number of Execution rows for step that
+// count toward RestartPolicy.MaxAttempts within the policy's rolling Window.
+// Which statuses count is governed by Condition: RestartOnFailure (the
+// default) only counts Failed executions, RestartOnAny counts every
+// execution regardless of outcome, and RestartNone never counts any (see
+// ShouldRestart, which short-circuits separately for that case). A zero
+// Window disables the rolling cutoff and counts every matching execution for
+// the step.
+func (p RestartPolicy) CountFailuresInWindow(db *gorm.DB, stepID uint, now time.Time) (int64, error) {
+	var count int64
+	if p.Condition == RestartNone {
+		return 0, nil
+	}
+	query := db.Model(&Execution{}).Where("step_id = ?", stepID)
+	if p.Condition != RestartOnAny {
+		query = query.Where("status = ?", Failed)
+	}
+	if p.Window > 0 {
+		query = query.Where("timestamp >= ?", now.Add(-p.Window))
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ShouldRestart reports whether, given the current attempt count for a step,
+// the engine should schedule another attempt under this policy.
+// defaultMaxAttempts is the engine-wide StepMaxRetries, used in place of a
+// zero MaxAttempts.
+func (p RestartPolicy) ShouldRestart(attempts int64, defaultMaxAttempts int) bool {
+	if p.Condition == RestartNone {
+		return false
+	}
+	maxAttempts := int64(p.MaxAttempts)
+	if maxAttempts == 0 {
+		maxAttempts = int64(defaultMaxAttempts)
+	}
+	return attempts < maxAttempts
+}
@@ -0,0 +1,278 @@
+// Package poller drives Azure Resource Manager long-running operations
+// (LROs) to completion, persisting enough state in model.AsyncOperation to
+// resume after a process restart.
+package poller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/tznamena/azure-aap-deployment-driver/server/model"
+)
+
+// azureOperationStatus is the standard ARM LRO polling response body.
+type azureOperationStatus struct {
+	Status string `json:"status"`
+	Error  *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// azureResource is the shape of an ARM resource GET response, used for the
+// final PUT/PATCH resource fetch. Unlike the operation-status polling body,
+// provisioningState here is nested under "properties".
+type azureResource struct {
+	Properties struct {
+		ProvisioningState string `json:"provisioningState"`
+	} `json:"properties"`
+}
+
+// Poller tracks model.AsyncOperation rows and drives each one to a terminal
+// state, feeding the result back through model.UpdateExecution.
+type Poller struct {
+	db     *gorm.DB
+	client *http.Client
+	// maxInterval caps the poll interval at EngineConfiguration.StepDeploymentTimeout.
+	maxInterval time.Duration
+}
+
+// NewPoller builds a Poller. maxInterval should be set from
+// EngineConfiguration.StepDeploymentTimeout so a runaway Retry-After header
+// can never push a poll out past the step's own deployment timeout.
+func NewPoller(db *gorm.DB, client *http.Client, maxInterval time.Duration) *Poller {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Poller{db: db, client: client, maxInterval: maxInterval}
+}
+
+// Resume reloads every non-terminal AsyncOperation from the database and
+// restarts polling for it. Call once on engine startup.
+func (p *Poller) Resume(ctx context.Context) error {
+	var ops []model.AsyncOperation
+	if err := p.db.Where("terminal_state = ?", "").Find(&ops).Error; err != nil {
+		return fmt.Errorf("loading pending async operations: %w", err)
+	}
+	for _, op := range ops {
+		go p.run(ctx, op)
+	}
+	return nil
+}
+
+// Track persists a newly submitted ARM request's LRO details and begins
+// polling it.
+func (p *Poller) Track(ctx context.Context, op model.AsyncOperation) error {
+	if err := p.db.Create(&op).Error; err != nil {
+		return fmt.Errorf("persisting async operation: %w", err)
+	}
+	go p.run(ctx, op)
+	return nil
+}
+
+func (p *Poller) run(ctx context.Context, op model.AsyncOperation) {
+	for {
+		wait := op.RetryAfter()
+		if wait <= 0 {
+			wait = model.DefaultAsyncOperationRetryAfter
+		}
+		if p.maxInterval > 0 && wait > p.maxInterval {
+			wait = p.maxInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		next, done, err := p.pollOnce(ctx, op)
+		if err != nil {
+			log.Warnf("polling async operation %d (%s): %v", op.ID, op.OperationURL, err)
+			continue
+		}
+		op = next
+		if done {
+			return
+		}
+	}
+}
+
+// pollOnce issues a single GET against op.OperationURL and either persists
+// progress or resolves the terminal result into the owning Execution.
+func (p *Poller) pollOnce(ctx context.Context, op model.AsyncOperation) (model.AsyncOperation, bool, error) {
+	status, retryAfter, err := p.getOperationStatus(ctx, op.OperationURL)
+	if err != nil {
+		return op, false, err
+	}
+
+	op.LastPolledAt = time.Now()
+	if retryAfter > 0 {
+		op.RetryAfterSec = int64(retryAfter.Seconds())
+	}
+
+	switch status.Status {
+	case "Succeeded", "Failed", "Canceled":
+	default:
+		if err := p.db.Save(&op).Error; err != nil {
+			return op, false, fmt.Errorf("persisting poll progress: %w", err)
+		}
+		return op, false, nil
+	}
+
+	op.TerminalState = status.Status
+
+	var execution model.Execution
+	if err := p.db.First(&execution, op.ExecutionID).Error; err != nil {
+		return op, false, fmt.Errorf("loading execution %d: %w", op.ExecutionID, err)
+	}
+
+	result, errJSON, err := p.resolveTerminalResult(ctx, op, execution, status)
+	if err != nil {
+		return op, false, err
+	}
+
+	model.UpdateExecution(&execution, result, errJSON)
+	if err := p.db.Save(&execution).Error; err != nil {
+		return op, false, fmt.Errorf("saving execution %d: %w", op.ExecutionID, err)
+	}
+	if err := p.db.Save(&op).Error; err != nil {
+		return op, false, fmt.Errorf("persisting terminal async operation: %w", err)
+	}
+	return op, true, nil
+}
+
+// resolveTerminalResult builds the DeploymentResult to feed into
+// UpdateExecution. The outcome is decided by status.Status itself
+// ("Succeeded" vs. "Failed"/"Canceled"), not merely by whether an inline
+// error object is present: ARM commonly reports a cancellation, and
+// sometimes a failure, with no "error" field at all. For PUT/PATCH, ARM
+// requires a final GET against the original resource URI rather than
+// trusting the operation body; for POST/DELETE the operation body is
+// authoritative. CorrelationID and DeploymentID are carried forward from the
+// operation/execution rather than left zero, since UpdateExecution copies
+// them onto the execution unconditionally and would otherwise wipe out the
+// values recorded when the deployment was first submitted.
+func (p *Poller) resolveTerminalResult(ctx context.Context, op model.AsyncOperation, execution model.Execution, status azureOperationStatus) (*model.DeploymentResult, string, error) {
+	if status.Status != "Succeeded" {
+		return p.terminalErrorJSON(status)
+	}
+
+	provisioningState := ""
+	if op.Method == http.MethodPut || op.Method == http.MethodPatch {
+		resource, err := p.getFinalResource(ctx, op.OriginalURI)
+		if err != nil {
+			return nil, "", fmt.Errorf("fetching final resource %s: %w", op.OriginalURI, err)
+		}
+		provisioningState = resource.Properties.ProvisioningState
+	}
+
+	correlationID := op.CorrelationID
+	if correlationID == "" {
+		correlationID = execution.CorrelationID
+	}
+
+	return &model.DeploymentResult{
+		ID:                execution.DeploymentID,
+		Status:            model.Succeeded,
+		CorrelationID:     correlationID,
+		ProvisioningState: provisioningState,
+		Timestamp:         time.Now(),
+	}, "", nil
+}
+
+// terminalErrorJSON builds the errJSON UpdateExecution expects for a
+// non-Succeeded terminal status. When ARM supplied an inline error it is
+// passed through as-is; otherwise (e.g. a bare {"status":"Canceled"}) a
+// minimal error body is synthesized from the status itself so the failure
+// isn't silently dropped.
+func (p *Poller) terminalErrorJSON(status azureOperationStatus) (*model.DeploymentResult, string, error) {
+	errorBody := struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}{}
+	if status.Error != nil {
+		errorBody.Error = *status.Error
+	} else {
+		errorBody.Error.Code = status.Status
+		errorBody.Error.Message = fmt.Sprintf("operation finished with status %q", status.Status)
+	}
+
+	errJSON, err := json.Marshal(errorBody)
+	if err != nil {
+		return nil, "", fmt.Errorf("marshalling terminal error: %w", err)
+	}
+	return nil, string(errJSON), nil
+}
+
+func (p *Poller) getOperationStatus(ctx context.Context, url string) (azureOperationStatus, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return azureOperationStatus{}, 0, fmt.Errorf("building poll request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return azureOperationStatus{}, 0, fmt.Errorf("polling %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return azureOperationStatus{}, 0, fmt.Errorf("reading poll response: %w", err)
+	}
+
+	var status azureOperationStatus
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &status); err != nil {
+			return azureOperationStatus{}, 0, fmt.Errorf("parsing poll response: %w", err)
+		}
+	}
+
+	var retryAfter time.Duration
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+
+	return status, retryAfter, nil
+}
+
+// getFinalResource issues the final GET against a PUT/PATCH's original
+// resource URI once its LRO reaches a terminal state. It decodes into
+// azureResource rather than azureOperationStatus, since a resource GET
+// nests provisioningState under "properties" instead of at the top level.
+func (p *Poller) getFinalResource(ctx context.Context, url string) (azureResource, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return azureResource{}, fmt.Errorf("building final resource request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return azureResource{}, fmt.Errorf("fetching %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return azureResource{}, fmt.Errorf("reading final resource response: %w", err)
+	}
+
+	var resource azureResource
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &resource); err != nil {
+			return azureResource{}, fmt.Errorf("parsing final resource response: %w", err)
+		}
+	}
+	return resource, nil
+}